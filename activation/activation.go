@@ -0,0 +1,58 @@
+// Package activation implements the systemd socket activation protocol,
+// letting a process launched from a .socket unit pick up the listeners
+// systemd bound on its behalf, via the LISTEN_FDS/LISTEN_PID/LISTEN_FDNAMES
+// environment variables.
+package activation
+
+import (
+	"os"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+// listenFdsStart is the file descriptor systemd starts passing listeners
+// at; 0, 1, and 2 are stdin/stdout/stderr.
+const listenFdsStart = 3
+
+// Files returns the set of file descriptors passed to this process by
+// systemd, in the same order systemd passed them. If unsetEnv is true, the
+// process environment is cleared of LISTEN_PID, LISTEN_FDS, and
+// LISTEN_FDNAMES so that child processes don't also try to consume them.
+func Files(unsetEnv bool) []*os.File {
+	if unsetEnv {
+		defer unsetEnvironment()
+	}
+
+	pid, err := strconv.Atoi(os.Getenv("LISTEN_PID"))
+	if err != nil || pid != os.Getpid() {
+		return nil
+	}
+
+	nfds, err := strconv.Atoi(os.Getenv("LISTEN_FDS"))
+	if err != nil || nfds == 0 {
+		return nil
+	}
+
+	names := strings.Split(os.Getenv("LISTEN_FDNAMES"), ":")
+
+	files := make([]*os.File, 0, nfds)
+	for i := listenFdsStart; i < listenFdsStart+nfds; i++ {
+		syscall.CloseOnExec(i)
+
+		name := "LISTEN_FD_" + strconv.Itoa(i)
+		if offset := i - listenFdsStart; offset < len(names) && names[offset] != "" {
+			name = names[offset]
+		}
+
+		files = append(files, os.NewFile(uintptr(i), name))
+	}
+
+	return files
+}
+
+func unsetEnvironment() {
+	os.Unsetenv("LISTEN_PID")
+	os.Unsetenv("LISTEN_FDS")
+	os.Unsetenv("LISTEN_FDNAMES")
+}
@@ -0,0 +1,81 @@
+package activation
+
+import (
+	"crypto/tls"
+	"net"
+)
+
+// Listeners returns net.Listeners for all socket-activated file
+// descriptors passed to this process by systemd.
+func Listeners() ([]net.Listener, error) {
+	files := Files(true)
+	listeners := make([]net.Listener, 0, len(files))
+
+	for _, f := range files {
+		if l, err := net.FileListener(f); err == nil {
+			listeners = append(listeners, l)
+			f.Close()
+		} else {
+			return nil, err
+		}
+	}
+
+	return listeners, nil
+}
+
+// TLSListeners is like Listeners, but wraps each listener with cfg via
+// tls.NewListener.
+func TLSListeners(cfg *tls.Config) ([]net.Listener, error) {
+	listeners, err := Listeners()
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg == nil {
+		return listeners, nil
+	}
+
+	for i, l := range listeners {
+		listeners[i] = tls.NewListener(l, cfg)
+	}
+
+	return listeners, nil
+}
+
+// PacketConns returns net.PacketConns for all socket-activated file
+// descriptors passed to this process by systemd.
+func PacketConns() ([]net.PacketConn, error) {
+	files := Files(true)
+	conns := make([]net.PacketConn, 0, len(files))
+
+	for _, f := range files {
+		if c, err := net.FilePacketConn(f); err == nil {
+			conns = append(conns, c)
+			f.Close()
+		} else {
+			return nil, err
+		}
+	}
+
+	return conns, nil
+}
+
+// ListenersWithNames is like Listeners, but returns a map from the names
+// systemd assigned each socket (via FileDescriptorName in the .socket
+// unit) to the listeners created from it.
+func ListenersWithNames() (map[string][]net.Listener, error) {
+	files := Files(true)
+	listeners := make(map[string][]net.Listener)
+
+	for _, f := range files {
+		l, err := net.FileListener(f)
+		if err != nil {
+			return nil, err
+		}
+		f.Close()
+
+		listeners[f.Name()] = append(listeners[f.Name()], l)
+	}
+
+	return listeners, nil
+}
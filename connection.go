@@ -29,6 +29,18 @@ type Conn struct {
 		reloadCh chan<- bool
 		sync.Mutex
 	}
+
+	ignore struct {
+		units     map[string]int64
+		lastClean int64
+		sync.Mutex
+	}
+
+	unitsSubs struct {
+		list            []*unitsSubscription
+		matchRegistered bool
+		sync.Mutex
+	}
 }
 
 func (c *Conn) Raw() *dbus.Conn {
@@ -99,6 +111,9 @@ func NewConnection(dialBus func() (*dbus.Conn, error)) (*Conn, error) {
 	// Setup the listeners on jobs so that we can get completions
 	c.sigconn.BusObject().Call("org.freedesktop.DBus.AddMatch", 0,
 		"type='signal', interface='org.freedesktop.systemd1.Manager', member='JobRemoved'")
+
+	// UnitNew/UnitRemoved are only watched once a SubscribeUnits caller
+	// actually needs them; see addUnitsSubscription.
 	c.dispatch()
 	return c, nil
 }
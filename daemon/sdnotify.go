@@ -0,0 +1,102 @@
+// Package daemon lets processes managed by systemd's Type=notify services
+// report readiness, status, and watchdog keepalives via the sd_notify
+// protocol, without linking against libsystemd.
+package daemon
+
+import (
+	"net"
+	"os"
+	"strconv"
+	"time"
+)
+
+const (
+	// SdNotifyReady tells systemd that service startup is finished, or
+	// that the service finished reloading its configuration.
+	SdNotifyReady = "READY=1"
+
+	// SdNotifyStopping tells systemd that the service is beginning its
+	// shutdown.
+	SdNotifyStopping = "STOPPING=1"
+
+	// SdNotifyReloading tells systemd that the service is reloading its
+	// configuration.
+	SdNotifyReloading = "RELOADING=1"
+
+	// SdNotifyWatchdog tells systemd to update the watchdog timestamp for
+	// the service.
+	SdNotifyWatchdog = "WATCHDOG=1"
+)
+
+// SdNotify sends a message to the init daemon through the NOTIFY_SOCKET
+// environment variable. It returns (false, nil) if NOTIFY_SOCKET isn't
+// set, which is the case when the service wasn't started by systemd, or
+// is not of Type=notify. If unsetEnvironment is true, NOTIFY_SOCKET is
+// unset before returning.
+func SdNotify(unsetEnvironment bool, state string) (bool, error) {
+	socketAddr := &net.UnixAddr{
+		Name: os.Getenv("NOTIFY_SOCKET"),
+		Net:  "unixgram",
+	}
+
+	if unsetEnvironment {
+		defer os.Unsetenv("NOTIFY_SOCKET")
+	}
+
+	if socketAddr.Name == "" {
+		return false, nil
+	}
+
+	if socketAddr.Name[0] == '@' {
+		socketAddr.Name = "\x00" + socketAddr.Name[1:]
+	}
+
+	conn, err := net.DialUnix(socketAddr.Net, nil, socketAddr)
+	if err != nil {
+		return false, err
+	}
+	defer conn.Close()
+
+	if _, err = conn.Write([]byte(state)); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// SdWatchdogEnabled reports whether the service should ping the systemd
+// watchdog, and if so the recommended interval to do so at: half of
+// WATCHDOG_USEC, to leave headroom before systemd considers the service
+// unresponsive. If unsetEnvironment is true, WATCHDOG_USEC and
+// WATCHDOG_PID are unset before returning. A zero duration means the
+// watchdog is not enabled for this process.
+func SdWatchdogEnabled(unsetEnvironment bool) (time.Duration, error) {
+	if unsetEnvironment {
+		defer os.Unsetenv("WATCHDOG_USEC")
+		defer os.Unsetenv("WATCHDOG_PID")
+	}
+
+	wusec := os.Getenv("WATCHDOG_USEC")
+	if wusec == "" {
+		return 0, nil
+	}
+	usec, err := strconv.Atoi(wusec)
+	if err != nil {
+		return 0, err
+	}
+	if usec <= 0 {
+		return 0, nil
+	}
+
+	if wpid := os.Getenv("WATCHDOG_PID"); wpid != "" {
+		pid, err := strconv.Atoi(wpid)
+		if err != nil {
+			return 0, err
+		}
+		if pid != os.Getpid() {
+			return 0, nil
+		}
+	}
+
+	return time.Duration(usec) * time.Microsecond / 2, nil
+}
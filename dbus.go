@@ -0,0 +1,68 @@
+package dbusext
+
+import (
+	"encoding/hex"
+
+	"github.com/godbus/dbus"
+)
+
+const unitPathPrefix = "/org/freedesktop/systemd1/unit/"
+
+func needsEscape(i int, b byte) bool {
+	// Escape everything that is not a-z, A-Z, 0-9, and do not allow digits
+	// to be the first character of the path.
+	if 'a' <= b && b <= 'z' || 'A' <= b && b <= 'Z' || (i > 0 && '0' <= b && b <= '9') {
+		return false
+	}
+	return true
+}
+
+// PathBusEscape sanitizes a constituent string of a dbus ObjectPath using
+// the same algorithm systemd uses to turn unit names into object paths:
+// any byte outside [a-zA-Z0-9] (and any leading digit) is replaced by "_"
+// followed by its two-digit hex encoding.
+func PathBusEscape(path string) string {
+	if len(path) == 0 {
+		return "_"
+	}
+
+	n := make([]byte, 0, len(path))
+	for i := 0; i < len(path); i++ {
+		c := path[i]
+		if needsEscape(i, c) {
+			n = append(n, '_')
+			n = append(n, []byte(hex.EncodeToString([]byte{c}))...)
+		} else {
+			n = append(n, c)
+		}
+	}
+	return string(n)
+}
+
+// PathBusUnescape reverses PathBusEscape. Malformed "_XX" sequences are
+// left untouched rather than rejected.
+func PathBusUnescape(path string) string {
+	if path == "_" {
+		return ""
+	}
+	n := make([]byte, 0, len(path))
+	for i := 0; i < len(path); i++ {
+		c := path[i]
+		if c == '_' && i+2 < len(path) {
+			if b, err := hex.DecodeString(path[i+1 : i+3]); err == nil {
+				n = append(n, b...)
+				i += 2
+				continue
+			}
+		}
+		n = append(n, c)
+	}
+	return string(n)
+}
+
+// GetUnitPath returns the object path systemd would assign to the unit
+// with the given name, computed locally rather than via a GetUnit
+// round-trip.
+func (c *Conn) GetUnitPath(name string) dbus.ObjectPath {
+	return dbus.ObjectPath(unitPathPrefix + PathBusEscape(name))
+}
@@ -0,0 +1,299 @@
+package dbusext
+
+import (
+	"fmt"
+
+	"github.com/godbus/dbus"
+)
+
+func (c *Conn) startJob(ch chan<- string, job string, args ...interface{}) (dbus.ObjectPath, error) {
+	if ch != nil {
+		c.jobListener.Lock()
+		defer c.jobListener.Unlock()
+	}
+
+	var path dbus.ObjectPath
+	err := c.sysobj.Call(job, 0, args...).Store(&path)
+	if err != nil {
+		return "", err
+	}
+
+	if ch != nil {
+		c.jobListener.jobs[path] = ch
+	}
+
+	return path, nil
+}
+
+// jobComplete looks up the channel registered for the job referenced by a
+// JobRemoved signal and, if found, delivers the job's result and forgets
+// about it.
+func (c *Conn) jobComplete(signal *dbus.Signal) {
+	var id uint32
+	var job dbus.ObjectPath
+	var unit string
+	var result string
+	dbus.Store(signal.Body, &id, &job, &unit, &result)
+
+	c.jobListener.Lock()
+	ch, ok := c.jobListener.jobs[job]
+	if ok {
+		delete(c.jobListener.jobs, job)
+	}
+	c.jobListener.Unlock()
+
+	if ok {
+		ch <- result
+	}
+}
+
+// StartUnit enqueues a start job for the given unit and, if ch is
+// non-nil, reports the job result on ch once the job completes.
+func (c *Conn) StartUnit(name string, mode string, ch chan<- string) error {
+	_, err := c.startJob(ch, "org.freedesktop.systemd1.Manager.StartUnit", name, mode)
+	return err
+}
+
+// StopUnit enqueues a stop job for the given unit.
+func (c *Conn) StopUnit(name string, mode string, ch chan<- string) error {
+	_, err := c.startJob(ch, "org.freedesktop.systemd1.Manager.StopUnit", name, mode)
+	return err
+}
+
+// ReloadUnit enqueues a reload job for the given unit.
+func (c *Conn) ReloadUnit(name string, mode string, ch chan<- string) error {
+	_, err := c.startJob(ch, "org.freedesktop.systemd1.Manager.ReloadUnit", name, mode)
+	return err
+}
+
+// RestartUnit enqueues a restart job for the given unit.
+func (c *Conn) RestartUnit(name string, mode string, ch chan<- string) error {
+	_, err := c.startJob(ch, "org.freedesktop.systemd1.Manager.RestartUnit", name, mode)
+	return err
+}
+
+// TryRestartUnit is like RestartUnit, except that a job is only enqueued
+// if the unit is already running.
+func (c *Conn) TryRestartUnit(name string, mode string, ch chan<- string) error {
+	_, err := c.startJob(ch, "org.freedesktop.systemd1.Manager.TryRestartUnit", name, mode)
+	return err
+}
+
+// ReloadOrRestartUnit attempts a reload if the unit supports it, and
+// performs a restart otherwise.
+func (c *Conn) ReloadOrRestartUnit(name string, mode string, ch chan<- string) error {
+	_, err := c.startJob(ch, "org.freedesktop.systemd1.Manager.ReloadOrRestartUnit", name, mode)
+	return err
+}
+
+// ReloadOrTryRestartUnit is like ReloadOrRestartUnit, except that it only
+// restarts the unit if it is already running.
+func (c *Conn) ReloadOrTryRestartUnit(name string, mode string, ch chan<- string) error {
+	_, err := c.startJob(ch, "org.freedesktop.systemd1.Manager.ReloadOrTryRestartUnit", name, mode)
+	return err
+}
+
+// KillUnit sends the given signal to all processes of the unit.
+func (c *Conn) KillUnit(name string, signal int32) error {
+	return c.sysobj.Call("org.freedesktop.systemd1.Manager.KillUnit", 0, name, "all", signal).Store()
+}
+
+// ResetFailedUnit resets the "failed" state of a unit.
+func (c *Conn) ResetFailedUnit(name string) error {
+	return c.sysobj.Call("org.freedesktop.systemd1.Manager.ResetFailedUnit", 0, name).Store()
+}
+
+// UnitFileChange describes a single change made to a unit file by
+// EnableUnitFiles, DisableUnitFiles, LinkUnitFiles, MaskUnitFiles, or
+// UnmaskUnitFiles.
+type UnitFileChange struct {
+	Type        string // Type of the change (symlink or unlink)
+	Filename    string // File name of the symlink
+	Destination string // Destination of the symlink
+}
+
+func unitFileChangesFromStruct(changes [][]interface{}) ([]UnitFileChange, error) {
+	result := make([]UnitFileChange, len(changes))
+	for i, c := range changes {
+		if err := dbus.Store(c, &result[i].Type, &result[i].Filename, &result[i].Destination); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// EnableUnitFiles enables one or more units in the system.
+func (c *Conn) EnableUnitFiles(files []string, runtime bool, force bool) (bool, []UnitFileChange, error) {
+	var carriesInstallInfo bool
+	var changes [][]interface{}
+
+	err := c.sysobj.Call("org.freedesktop.systemd1.Manager.EnableUnitFiles", 0, files, runtime, force).Store(&carriesInstallInfo, &changes)
+	if err != nil {
+		return false, nil, err
+	}
+
+	result, err := unitFileChangesFromStruct(changes)
+	return carriesInstallInfo, result, err
+}
+
+// DisableUnitFiles disables one or more units in the system.
+func (c *Conn) DisableUnitFiles(files []string, runtime bool) ([]UnitFileChange, error) {
+	var changes [][]interface{}
+
+	err := c.sysobj.Call("org.freedesktop.systemd1.Manager.DisableUnitFiles", 0, files, runtime).Store(&changes)
+	if err != nil {
+		return nil, err
+	}
+
+	return unitFileChangesFromStruct(changes)
+}
+
+// LinkUnitFiles links unit files that are located outside of the usual
+// unit search paths into it.
+func (c *Conn) LinkUnitFiles(files []string, runtime bool, force bool) ([]UnitFileChange, error) {
+	var changes [][]interface{}
+
+	err := c.sysobj.Call("org.freedesktop.systemd1.Manager.LinkUnitFiles", 0, files, runtime, force).Store(&changes)
+	if err != nil {
+		return nil, err
+	}
+
+	return unitFileChangesFromStruct(changes)
+}
+
+// MaskUnitFiles masks one or more units in the system.
+func (c *Conn) MaskUnitFiles(files []string, runtime bool, force bool) ([]UnitFileChange, error) {
+	var changes [][]interface{}
+
+	err := c.sysobj.Call("org.freedesktop.systemd1.Manager.MaskUnitFiles", 0, files, runtime, force).Store(&changes)
+	if err != nil {
+		return nil, err
+	}
+
+	return unitFileChangesFromStruct(changes)
+}
+
+// UnmaskUnitFiles unmasks one or more units in the system.
+func (c *Conn) UnmaskUnitFiles(files []string, runtime bool) ([]UnitFileChange, error) {
+	var changes [][]interface{}
+
+	err := c.sysobj.Call("org.freedesktop.systemd1.Manager.UnmaskUnitFiles", 0, files, runtime).Store(&changes)
+	if err != nil {
+		return nil, err
+	}
+
+	return unitFileChangesFromStruct(changes)
+}
+
+// Reload instructs systemd to reparse its configuration.
+func (c *Conn) Reload() error {
+	return c.sysobj.Call("org.freedesktop.systemd1.Manager.Reload", 0).Store()
+}
+
+func unitStatusFromStruct(status [][]interface{}) ([]UnitStatus, error) {
+	result := make([]UnitStatus, len(status))
+	for i, u := range status {
+		if len(u) < 10 {
+			return nil, fmt.Errorf("dbusext: invalid unit status with %d fields", len(u))
+		}
+		if err := dbus.Store(u, &result[i].Name, &result[i].Description, &result[i].LoadState,
+			&result[i].ActiveState, &result[i].SubState, &result[i].Followed, &result[i].Path,
+			&result[i].JobId, &result[i].JobType, &result[i].JobPath); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// ListUnits returns an array with all currently loaded units.
+func (c *Conn) ListUnits() ([]UnitStatus, error) {
+	var status [][]interface{}
+
+	err := c.sysobj.Call("org.freedesktop.systemd1.Manager.ListUnits", 0).Store(&status)
+	if err != nil {
+		return nil, err
+	}
+
+	return unitStatusFromStruct(status)
+}
+
+// ListUnitsByNames is like ListUnits but restricted to the given unit
+// names, whether or not they are currently loaded.
+func (c *Conn) ListUnitsByNames(units []string) ([]UnitStatus, error) {
+	var status [][]interface{}
+
+	err := c.sysobj.Call("org.freedesktop.systemd1.Manager.ListUnitsByNames", 0, units).Store(&status)
+	if err != nil {
+		return nil, err
+	}
+
+	return unitStatusFromStruct(status)
+}
+
+// UnitFile describes a single entry returned by ListUnitFiles.
+type UnitFile struct {
+	Path string
+	Type string
+}
+
+// ListUnitFiles returns an array of all unit files installed on the
+// system, irrespective of whether they are currently loaded.
+func (c *Conn) ListUnitFiles() ([]UnitFile, error) {
+	var files [][]interface{}
+
+	err := c.sysobj.Call("org.freedesktop.systemd1.Manager.ListUnitFiles", 0).Store(&files)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]UnitFile, len(files))
+	for i, f := range files {
+		if err := dbus.Store(f, &result[i].Path, &result[i].Type); err != nil {
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
+
+// GetUnitProperties takes a unit name and returns all of its dbus object
+// properties.
+func (c *Conn) GetUnitProperties(unit string) (map[string]interface{}, error) {
+	var path dbus.ObjectPath
+	err := c.sysobj.Call("org.freedesktop.systemd1.Manager.GetUnit", 0, unit).Store(&path)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.GetUnitPropertiesFromObjectPath(path)
+}
+
+// GetServiceProperty returns a single property of a unit's
+// org.freedesktop.systemd1.Service interface.
+func (c *Conn) GetServiceProperty(service string, propertyName string) (*Property, error) {
+	var path dbus.ObjectPath
+	err := c.sysobj.Call("org.freedesktop.systemd1.Manager.GetUnit", 0, service).Store(&path)
+	if err != nil {
+		return nil, err
+	}
+
+	variant, err := c.sysconn.Object("org.freedesktop.systemd1", path).GetProperty("org.freedesktop.systemd1.Service." + propertyName)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Property{Name: propertyName, Value: variant}, nil
+}
+
+// SetUnitProperties sets one or more properties of a unit that supports
+// transient changes, such as resource control settings.
+func (c *Conn) SetUnitProperties(name string, runtime bool, properties ...Property) error {
+	return c.sysobj.Call("org.freedesktop.systemd1.Manager.SetUnitProperties", 0, name, runtime, properties).Store()
+}
+
+// StartTransientUnit creates and starts a transient unit with the given
+// name and properties, without requiring a unit file on disk.
+func (c *Conn) StartTransientUnit(name string, mode string, properties []Property, aux []PropertyCollection, ch chan<- string) error {
+	_, err := c.startJob(ch, "org.freedesktop.systemd1.Manager.StartTransientUnit", name, mode, properties, aux)
+	return err
+}
@@ -0,0 +1,73 @@
+// Package properties provides constructors for the dbusext.Property values
+// commonly passed to Conn.StartTransientUnit and Conn.SetUnitProperties,
+// matching the signatures systemd expects on the wire.
+package properties
+
+import (
+	"errors"
+
+	"github.com/MartB/dbusext"
+	"github.com/godbus/dbus"
+)
+
+// Description sets the human readable description of the unit.
+func Description(desc string) dbusext.Property {
+	return dbusext.Property{
+		Name:  "Description",
+		Value: dbus.MakeVariant(desc),
+	}
+}
+
+// execStart mirrors the struct systemd expects for the ExecStart property:
+// an array of (path, argv, unclean-is-failure) tuples.
+type execStart struct {
+	Path             string   // the binary path to execute
+	Args             []string // full argument vector, including argv[0]
+	UncleanIsFailure bool     // whether a non-clean exit is treated as failure
+}
+
+// ExecStart sets the command a service unit executes. uncleanIsFailure
+// controls whether a non-clean exit code fails the unit. command must have
+// at least one element (the binary path).
+func ExecStart(command []string, uncleanIsFailure bool) (dbusext.Property, error) {
+	if len(command) == 0 {
+		return dbusext.Property{}, errors.New("properties: ExecStart requires a non-empty command")
+	}
+
+	execStarts := []execStart{{
+		Path:             command[0],
+		Args:             command,
+		UncleanIsFailure: uncleanIsFailure,
+	}}
+
+	return dbusext.Property{
+		Name:  "ExecStart",
+		Value: dbus.MakeVariant(execStarts),
+	}, nil
+}
+
+// Slice assigns the unit to the given slice.
+func Slice(slice string) dbusext.Property {
+	return dbusext.Property{
+		Name:  "Slice",
+		Value: dbus.MakeVariant(slice),
+	}
+}
+
+// CPUQuota sets the CPU time quota, expressed as a percentage of a single
+// CPU (100 meaning one full core).
+func CPUQuota(percentage uint64) dbusext.Property {
+	return dbusext.Property{
+		Name:  "CPUQuotaPerSecUSec",
+		Value: dbus.MakeVariant(percentage * 10000),
+	}
+}
+
+// MemoryLimit sets the absolute limit, in bytes, on memory usage for the
+// unit's control group.
+func MemoryLimit(limit uint64) dbusext.Property {
+	return dbusext.Property{
+		Name:  "MemoryLimit",
+		Value: dbus.MakeVariant(limit),
+	}
+}
@@ -0,0 +1,229 @@
+package dbusext
+
+import (
+	"errors"
+	"time"
+
+	"github.com/godbus/dbus"
+)
+
+// UnitStatus holds a subset of the properties for a unit as returned by
+// ListUnits and friends.
+type UnitStatus struct {
+	Name        string          // The primary unit name as string
+	Description string          // The human readable description string
+	LoadState   string          // The load state (i.e. whether the unit file has been loaded successfully)
+	ActiveState string          // The active state (i.e. whether the unit is currently started or not)
+	SubState    string          // The sub state (i.e. the more fine-grained state that is specific to the unit type, which the active state is mapped to)
+	Followed    string          // A unit that is being followed in its state by this unit, if there is any, otherwise the empty string
+	Path        dbus.ObjectPath // The unit object path
+	JobId       uint32          // If there is a job queued for the job unit, the numeric job id, 0 otherwise
+	JobType     string          // The job type as string
+	JobPath     dbus.ObjectPath // The job object path
+}
+
+const (
+	cleanIgnoreInterval = int64(10 * time.Second)
+	ignoreInterval      = int64(30 * time.Millisecond)
+)
+
+// SubscribeUnits returns two unbuffered channels which will receive all
+// changed units every interval.  Deleted units are sent as nil.
+func (c *Conn) SubscribeUnits(interval time.Duration) (<-chan map[string]*UnitStatus, <-chan error) {
+	return c.SubscribeUnitsCustom(interval, 0, mismatchUnitStatus, func(unit string) bool { return false })
+}
+
+// SubscribeUnitsCustom is like SubscribeUnits but lets the caller tune the
+// buffering of the returned channels, the function used to determine
+// whether a unit has changed, and a filter to ignore units that are not of
+// interest.
+func (c *Conn) SubscribeUnitsCustom(interval time.Duration, buffer int, isChanged func(*UnitStatus, *UnitStatus) bool, filterUnit func(string) bool) (<-chan map[string]*UnitStatus, <-chan error) {
+	old := make(map[string]*UnitStatus)
+	statusChan := make(chan map[string]*UnitStatus, buffer)
+	errChan := make(chan error, buffer)
+
+	// Units that appear and disappear between two polls would otherwise be
+	// missed entirely, since neither poll's ListUnits snapshot ever
+	// contains them. Registering here lets dispatch() push such transitions
+	// onto statusChan directly as UnitNew/UnitRemoved signals arrive.
+	c.addUnitsSubscription(&unitsSubscription{statusChan: statusChan, errChan: errChan, filterUnit: filterUnit})
+
+	go func() {
+		for {
+			timerChan := time.After(interval)
+
+			units, err := c.ListUnits()
+			if err == nil {
+				cur := make(map[string]*UnitStatus)
+				for i := range units {
+					u := units[i]
+
+					if filterUnit(u.Name) {
+						continue
+					}
+					cur[u.Name] = &u
+				}
+
+				c.cleanIgnore()
+
+				changed := make(map[string]*UnitStatus)
+				for n, u := range cur {
+					if c.shouldIgnore(n) {
+						continue
+					}
+					if oldU, ok := old[n]; !ok || isChanged(oldU, u) {
+						changed[n] = u
+					}
+				}
+
+				for n := range old {
+					if _, ok := cur[n]; !ok && !c.shouldIgnore(n) {
+						changed[n] = nil
+					}
+				}
+
+				old = cur
+
+				if len(changed) != 0 {
+					statusChan <- changed
+				}
+			} else {
+				errChan <- err
+			}
+
+			<-timerChan
+		}
+	}()
+
+	return statusChan, errChan
+}
+
+// mismatchUnitStatus returns true if the provided UnitStatus objects
+// indicate a change that should be reported by SubscribeUnits. This is the
+// default isChanged function, and compares the ActiveState and SubState
+// fields.
+func mismatchUnitStatus(u1, u2 *UnitStatus) bool {
+	return u1.ActiveState != u2.ActiveState || u1.SubState != u2.SubState
+}
+
+// unitsSubscription is a registered SubscribeUnits/SubscribeUnitsCustom
+// caller, kept around so dispatch() can push unit appearance/removal
+// straight onto its channel as the signals happen, rather than waiting for
+// the next poll.
+type unitsSubscription struct {
+	statusChan chan<- map[string]*UnitStatus
+	errChan    chan<- error
+	filterUnit func(string) bool
+}
+
+// addUnitsSubscription registers sub and, on the first registration, starts
+// watching UnitNew/UnitRemoved so dispatch() has signals to push from.
+// These match rules are deliberately not registered in NewConnection: every
+// Conn pays their signal traffic and the ignoreUnit bookkeeping they drive,
+// so they're only worth it once something actually calls SubscribeUnits.
+func (c *Conn) addUnitsSubscription(sub *unitsSubscription) {
+	c.unitsSubs.Lock()
+	defer c.unitsSubs.Unlock()
+	c.unitsSubs.list = append(c.unitsSubs.list, sub)
+
+	if !c.unitsSubs.matchRegistered {
+		c.unitsSubs.matchRegistered = true
+		c.sigconn.BusObject().Call("org.freedesktop.DBus.AddMatch", 0,
+			"type='signal', interface='org.freedesktop.systemd1.Manager', member='UnitNew'")
+		c.sigconn.BusObject().Call("org.freedesktop.DBus.AddMatch", 0,
+			"type='signal', interface='org.freedesktop.systemd1.Manager', member='UnitRemoved'")
+	}
+}
+
+// notifyUnitLifecycle pushes an immediate UnitNew/UnitRemoved transition to
+// every registered SubscribeUnits caller whose filter accepts unitName. For
+// a new unit, path is resolved to a UnitStatus on a best-effort basis; for
+// a removed unit, the update is a nil entry.
+//
+// This does a dbus round-trip, so dispatch() always runs it in its own
+// goroutine rather than inline. Sends to subscribers never block: if a
+// statusChan is not ready, an error is offered on errChan instead, and
+// dropped if errChan is not ready either.
+func (c *Conn) notifyUnitLifecycle(unitName string, path dbus.ObjectPath, removed bool) {
+	c.unitsSubs.Lock()
+	subs := make([]*unitsSubscription, len(c.unitsSubs.list))
+	copy(subs, c.unitsSubs.list)
+	c.unitsSubs.Unlock()
+
+	if len(subs) == 0 {
+		return
+	}
+
+	var status *UnitStatus
+	if !removed {
+		status = &UnitStatus{Name: unitName, Path: path}
+		if props, err := c.GetUnitPropertiesFromObjectPath(path); err == nil {
+			if v, ok := props["ActiveState"].(string); ok {
+				status.ActiveState = v
+			}
+			if v, ok := props["SubState"].(string); ok {
+				status.SubState = v
+			}
+			if v, ok := props["LoadState"].(string); ok {
+				status.LoadState = v
+			}
+			if v, ok := props["Description"].(string); ok {
+				status.Description = v
+			}
+		}
+	}
+
+	for _, sub := range subs {
+		if sub.filterUnit(unitName) {
+			continue
+		}
+		select {
+		case sub.statusChan <- map[string]*UnitStatus{unitName: status}:
+		default:
+			select {
+			case sub.errChan <- errors.New("units subscription channel full"):
+			default:
+			}
+		}
+	}
+}
+
+// ignoreUnit records that unitName was just reported via a signal, so a
+// poll landing within ignoreInterval of it does not also report the same
+// add/remove transition a second time.
+func (c *Conn) ignoreUnit(unitName string) {
+	c.ignore.Lock()
+	defer c.ignore.Unlock()
+	if c.ignore.units == nil {
+		c.ignore.units = make(map[string]int64)
+	}
+	c.ignore.units[unitName] = time.Now().UnixNano() + ignoreInterval
+}
+
+// shouldIgnore reports whether unitName was recently reported via a signal
+// and is still within its ignore window.
+func (c *Conn) shouldIgnore(unitName string) bool {
+	c.ignore.Lock()
+	defer c.ignore.Unlock()
+	expiry, ok := c.ignore.units[unitName]
+	return ok && time.Now().UnixNano() < expiry
+}
+
+// cleanIgnore periodically drops expired entries from the ignore set so it
+// doesn't grow without bound across the lifetime of the connection.
+func (c *Conn) cleanIgnore() {
+	c.ignore.Lock()
+	defer c.ignore.Unlock()
+
+	now := time.Now().UnixNano()
+	if now < c.ignore.lastClean+cleanIgnoreInterval {
+		return
+	}
+	c.ignore.lastClean = now
+
+	for unitName, expiry := range c.ignore.units {
+		if now >= expiry {
+			delete(c.ignore.units, unitName)
+		}
+	}
+}
@@ -57,6 +57,20 @@ func (c *Conn) dispatch() {
 				c.jobComplete(signal)
 			}
 
+			switch signal.Name {
+			case "org.freedesktop.systemd1.Manager.UnitNew":
+				unitName := signal.Body[0].(string)
+				unitPath := signal.Body[1].(dbus.ObjectPath)
+				c.ignoreUnit(unitName)
+				go c.notifyUnitLifecycle(unitName, unitPath, false)
+				continue
+			case "org.freedesktop.systemd1.Manager.UnitRemoved":
+				unitName := signal.Body[0].(string)
+				c.ignoreUnit(unitName)
+				go c.notifyUnitLifecycle(unitName, "", true)
+				continue
+			}
+
 			if c.subscriber.updateCh == nil {
 				continue
 			}
@@ -64,7 +78,10 @@ func (c *Conn) dispatch() {
 			var unitPath dbus.ObjectPath
 			switch signal.Name {
 			case "org.freedesktop.systemd1.Manager.JobRemoved":
-				c.sysobj.Call("org.freedesktop.systemd1.Manager.GetUnit", 0, signal.Body[2].(string)).Store(&unitPath)
+				// Computed locally to avoid the GetUnit round-trip; if the
+				// path turns out to be stale, sendSubStateUpdate reports the
+				// resulting dbus error on errCh rather than us probing here.
+				unitPath = c.GetUnitPath(signal.Body[2].(string))
 			case "org.freedesktop.DBus.Properties.PropertiesChanged":
 				if signal.Body[0].(string) == "org.freedesktop.systemd1.Unit" {
 					unitPath = signal.Path
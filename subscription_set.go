@@ -0,0 +1,69 @@
+package dbusext
+
+import (
+	"sync"
+	"time"
+)
+
+// SubscriptionSet watches a specific, caller-managed set of unit names
+// without racing on missed state changes between calls to ListUnits.
+type SubscriptionSet struct {
+	conn *Conn
+
+	sync.Mutex
+	units map[string]bool
+}
+
+// NewSubscriptionSet returns a new subscription set scoped to this
+// connection.
+func (c *Conn) NewSubscriptionSet() *SubscriptionSet {
+	return &SubscriptionSet{
+		conn:  c,
+		units: make(map[string]bool),
+	}
+}
+
+// Add adds a unit to the subscription set.
+func (s *SubscriptionSet) Add(unit string) {
+	s.Lock()
+	defer s.Unlock()
+	s.units[unit] = true
+}
+
+// Remove removes a unit from the subscription set.
+func (s *SubscriptionSet) Remove(unit string) {
+	s.Lock()
+	defer s.Unlock()
+	delete(s.units, unit)
+}
+
+// Values returns the unit names currently in the subscription set.
+func (s *SubscriptionSet) Values() []string {
+	s.Lock()
+	defer s.Unlock()
+	values := make([]string, 0, len(s.units))
+	for unit := range s.units {
+		values = append(values, unit)
+	}
+	return values
+}
+
+func (s *SubscriptionSet) contains(unit string) bool {
+	s.Lock()
+	defer s.Unlock()
+	return s.units[unit]
+}
+
+// Subscribe starts watching the units currently in the set, polling at
+// interval. Units added to or removed from the set after Subscribe is
+// called take effect on the next poll.
+func (s *SubscriptionSet) Subscribe(interval time.Duration) (<-chan map[string]*UnitStatus, <-chan error) {
+	return s.conn.SubscribeUnitsCustom(
+		interval,
+		0,
+		mismatchUnitStatus,
+		func(unit string) bool {
+			return !s.contains(unit)
+		},
+	)
+}